@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// expandTemplate replaces strftime-style tokens in template with fields of t.
+// Supported tokens: %Y (4-digit year), %y (2-digit year), %m (month), %d
+// (day of month), %H (hour), %M (minute) and %% for a literal %. Any other
+// %X sequence is kept verbatim.
+func expandTemplate(template string, t time.Time) string {
+	var sb strings.Builder
+
+	runes := []rune(template)
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		if c != '%' || i+1 >= len(runes) {
+			sb.WriteRune(c)
+			continue
+		}
+
+		i++
+
+		switch runes[i] {
+		case 'Y':
+			fmt.Fprintf(&sb, "%04d", t.Year())
+		case 'y':
+			fmt.Fprintf(&sb, "%02d", t.Year()%100)
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", int(t.Month()))
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case '%':
+			sb.WriteRune('%')
+		default:
+			sb.WriteRune('%')
+			sb.WriteRune(runes[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// statsSidecarPathname derives the companion ".stats.json" pathname for a
+// downloaded results file, e.g. "results/2024/01-dl.txt" ->
+// "results/2024/01-dl.stats.json".
+func statsSidecarPathname(pathname string) string {
+	ext := fileExtension(pathname)
+	base := strings.TrimSuffix(pathname, "."+ext)
+
+	return base + ".stats.json"
+}
+
+// writeStatsSidecar writes sortedStats as a JSON array of {number, count}
+// objects to pathname, so historical runs can be diffed later.
+func writeStatsSidecar(sortedStats *orderedmap.OrderedMap[int, int], pathname string) error {
+	counts := make([]numberCount, 0, sortedStats.Len())
+
+	for el := sortedStats.Front(); el != nil; el = el.Next() {
+		counts = append(counts, numberCount{Number: el.Key, Count: el.Value})
+	}
+
+	bytes, err := json.MarshalIndent(counts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(pathname, bytes, 0666)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+)
+
+var chartBarWidth = vg.Points(8)
+
+// buildFrequencyChart turns a sorted number->frequency map into a bar chart,
+// one bar per number, with the number itself as the X tick label.
+func buildFrequencyChart(sortedStats *orderedmap.OrderedMap[int, int]) (*plot.Plot, error) {
+	p := plot.New()
+
+	p.Title.Text = "Number frequency"
+	p.Y.Label.Text = "times drawn"
+
+	values := make(plotter.Values, 0, sortedStats.Len())
+	labels := make([]string, 0, sortedStats.Len())
+
+	for el := sortedStats.Front(); el != nil; el = el.Next() {
+		values = append(values, float64(el.Value))
+		labels = append(labels, fmt.Sprint(el.Key))
+	}
+
+	bars, err := plotter.NewBarChart(values, chartBarWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	bars.Color = plotutil.Color(0)
+
+	p.Add(bars)
+	p.NominalX(labels...)
+
+	return p, nil
+}
+
+// buildMonthlyFrequencyChart plots, for each number in numbers, how many
+// times it was drawn per calendar month across results. Results are
+// expected to already be sorted by sortResultEntrySlice.
+func buildMonthlyFrequencyChart(results []ResultEntry, numbers []int) (*plot.Plot, error) {
+	p := plot.New()
+
+	p.Title.Text = "Monthly frequency"
+	p.X.Label.Text = "month"
+	p.Y.Label.Text = "times drawn"
+	p.X.Tick.Marker = plot.TimeTicks{Format: "2006-01"}
+
+	months := make([]time.Time, 0)
+	monthIndex := make(map[time.Time]int)
+
+	perNumberPerMonth := make(map[int]map[time.Time]int)
+	for _, num := range numbers {
+		perNumberPerMonth[num] = make(map[time.Time]int)
+	}
+
+	for _, entry := range results {
+		month := time.Date(entry.dateTime.Year(), entry.dateTime.Month(), 1, 0, 0, 0, 0, CURRENT_LOCATION)
+
+		if _, ok := monthIndex[month]; !ok {
+			monthIndex[month] = len(months)
+			months = append(months, month)
+		}
+
+		for _, num := range entry.numbers {
+			if counts, ok := perNumberPerMonth[num]; ok {
+				counts[month]++
+			}
+		}
+	}
+
+	plotValues := make([]interface{}, 0, len(numbers)*2)
+
+	for _, num := range numbers {
+		points := make(plotter.XYs, len(months))
+
+		for i, month := range months {
+			points[i].X = float64(month.Unix())
+			points[i].Y = float64(perNumberPerMonth[num][month])
+		}
+
+		plotValues = append(plotValues, fmt.Sprintf("%d", num), points)
+	}
+
+	if err := plotutil.AddLinePoints(p, plotValues...); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// saveFrequencyChart renders sortedStats to pathname. format is the file
+// extension to use ("png" or "svg"); pathname's extension is rewritten to
+// match it, since gonum/plot picks its encoder from the extension alone.
+func saveFrequencyChart(sortedStats *orderedmap.OrderedMap[int, int], pathname string, format string) error {
+	p, err := buildFrequencyChart(sortedStats)
+	if err != nil {
+		return err
+	}
+
+	return p.Save(6*vg.Inch, 4*vg.Inch, withExtension(pathname, format))
+}
+
+// saveMonthlyFrequencyChart renders a per-month frequency chart for numbers
+// to pathname, in the given format ("png" or "svg"); as in
+// saveFrequencyChart, pathname's extension is rewritten to match format.
+func saveMonthlyFrequencyChart(results []ResultEntry, numbers []int, pathname string, format string) error {
+	p, err := buildMonthlyFrequencyChart(results, numbers)
+	if err != nil {
+		return err
+	}
+
+	return p.Save(8*vg.Inch, 4*vg.Inch, withExtension(pathname, format))
+}
+
+// withExtension returns pathname with its extension replaced by ext.
+func withExtension(pathname string, ext string) string {
+	base := strings.TrimSuffix(pathname, "."+fileExtension(pathname))
+
+	return base + "." + ext
+}
+
+// monthlyChartPathname derives the companion monthly chart pathname from
+// the histogram pathname, e.g. "stats.png" -> "stats.monthly.png".
+func monthlyChartPathname(pathname string) string {
+	ext := fileExtension(pathname)
+	base := strings.TrimSuffix(pathname, "."+ext)
+
+	return base + ".monthly." + ext
+}
+
+// fileExtension returns the file extension of pathname without the leading dot.
+func fileExtension(pathname string) string {
+	idx := strings.LastIndex(pathname, ".")
+
+	if idx == -1 {
+		return ""
+	}
+
+	return pathname[idx+1:]
+}
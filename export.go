@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// exportResults writes results to w in the given format ("csv", "json" or
+// "ndjson").
+func exportResults(results []ResultEntry, format string, w io.Writer) error {
+	switch format {
+	case "csv":
+		return exportCSV(results, w)
+	case "json":
+		return exportJSON(results, w)
+	case "ndjson":
+		return exportNDJSON(results, w)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+func exportCSV(results []ResultEntry, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"seqNo", "date", "n0", "n1", "n2", "n3", "n4", "n5", "game"}); err != nil {
+		return err
+	}
+
+	for _, entry := range results {
+		record := []string{
+			fmt.Sprint(entry.seqNo),
+			entry.dateTime.Format("2006-01-02"),
+		}
+
+		for _, num := range entry.numbers {
+			record = append(record, fmt.Sprint(num))
+		}
+
+		record = append(record, entry.game)
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+func exportJSON(results []ResultEntry, w io.Writer) error {
+	dtos := make([]resultEntryJSON, 0, len(results))
+
+	for _, entry := range results {
+		dtos = append(dtos, toResultEntryJSON(entry))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(dtos)
+}
+
+func exportNDJSON(results []ResultEntry, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	for _, entry := range results {
+		if err := encoder.Encode(toResultEntryJSON(entry)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,8 @@
 package main
 
 import (
-	"crypto/tls"
+	"flag"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -19,10 +18,9 @@ import (
 )
 
 const REPO_URL = "http://www.mbnet.com.pl/dl.txt"
-const RESULT_FILE_FORMAT = "%s-dl.txt"
 
 var RESULTS_PATHNAME, _ = filepath.Abs(filepath.Dir(os.Args[0]))
-var RESULTS_FULL_PATHNAME = RESULTS_PATHNAME + "/results"
+var DEFAULT_ARCHIVE_TEMPLATE = RESULTS_PATHNAME + "/results/%Y-%m-%d-dl.txt"
 var RESULT_FORMAT_RE = regexp.MustCompile(`^(?P<seqNo>\d+)\. (?P<day>\d+)\.(?P<month>\d+)\.(?P<year>\d+)\ (?P<n0>\d+)\,(?P<n1>\d+)\,(?P<n2>\d+)\,(?P<n3>\d+)\,(?P<n4>\d+)\,(?P<n5>\d+)$`)
 var CURRENT_LOCATION = time.Now().Location()
 
@@ -30,51 +28,7 @@ type ResultEntry struct {
 	seqNo    int
 	dateTime time.Time
 	numbers  [6]int
-}
-
-func downloadLastResultsFile() string {
-	now := time.Now()
-	formattedTime := fmt.Sprintf("%d-%02d-%02d",
-		now.Year(), now.Month(), now.Day())
-
-	latestResultPathname := RESULTS_FULL_PATHNAME + "/" + fmt.Sprintf(RESULT_FILE_FORMAT, formattedTime)
-
-	log.Println("Looking for", latestResultPathname)
-
-	exists := true
-
-	_, err := os.Stat(latestResultPathname)
-
-	if err != nil {
-		exists = false
-	}
-
-	if exists {
-		log.Println("Latest file with results already exists in", latestResultPathname)
-
-		return latestResultPathname
-	}
-
-	log.Println("Latest file with results does not exists, downloading", REPO_URL, "to", latestResultPathname)
-
-	response, err := http.Get(REPO_URL)
-
-	if err != nil {
-		log.Panicln("err", err)
-	}
-
-	resBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		log.Panicln("err", err)
-	}
-
-	err = os.WriteFile(latestResultPathname, resBody, 0666)
-
-	if err != nil {
-		log.Panicln("err", err)
-	}
-
-	return latestResultPathname
+	game     string
 }
 
 func findNamedMatches(regex *regexp.Regexp, str string) map[string]string {
@@ -97,15 +51,21 @@ func findNamedMatches(regex *regexp.Regexp, str string) map[string]string {
 	return results
 }
 
-func parseResultsFile(pathname string) ([]ResultEntry, error) {
+func parseResultsFile(pathname string, re *regexp.Regexp, game string) ([]ResultEntry, error) {
 	bytes, err := os.ReadFile(pathname)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return parseResultsString(string(bytes), re, game), nil
+}
+
+// parseResultsString parses a dl.txt-style line format into ResultEntry
+// values tagged with game, skipping lines that don't match re. Used both
+// for whole archive files and for partial (tail-only) downloads.
+func parseResultsString(resultsStr string, re *regexp.Regexp, game string) []ResultEntry {
 	parsedResults := make([]ResultEntry, 0)
-	resultsStr := string(bytes)
 
 	for _, line := range strings.Split(resultsStr, "\n") {
 		line := strings.TrimSpace(line)
@@ -114,7 +74,7 @@ func parseResultsFile(pathname string) ([]ResultEntry, error) {
 			continue
 		}
 
-		parsedLineRe := findNamedMatches(RESULT_FORMAT_RE, line)
+		parsedLineRe := findNamedMatches(re, line)
 
 		if len(parsedLineRe) < 10 {
 			continue
@@ -136,12 +96,13 @@ func parseResultsFile(pathname string) ([]ResultEntry, error) {
 			seqNo:    int(seqNo),
 			dateTime: time.Date(int(year), time.Month(month), int(day), 0, 0, 0, 0, CURRENT_LOCATION),
 			numbers:  [6]int{int(n0), int(n1), int(n2), int(n3), int(n4), int(n5)},
+			game:     game,
 		}
 
 		parsedResults = append(parsedResults, entry)
 	}
 
-	return parsedResults, nil
+	return parsedResults
 }
 
 func sortResultEntrySlice(s []ResultEntry) {
@@ -160,26 +121,30 @@ func findResultIndex(date time.Time, results []ResultEntry) int {
 	return -1
 }
 
+// getNumbersStatistics counts how often each number was drawn between
+// startDate and endDate (inclusive). Every number in [minNumber, maxNumber]
+// is present in the result, with 0 if it was never drawn; drawn numbers
+// outside that range are counted as well. game restricts the count to
+// entries tagged with that game; an empty game counts every entry.
 func getNumbersStatistics(
 	startDate time.Time,
 	endDate time.Time,
 	minNumber int,
 	maxNumber int,
-	results []ResultEntry) map[int]int {
+	game string,
+	results []ResultEntry) (map[int]int, error) {
 	stats := make(map[int]int)
 
 	startIndex := findResultIndex(startDate, results)
 
 	if startIndex == -1 {
-		log.Println("cannot find result from day", startDate.String())
-		return nil
+		return nil, fmt.Errorf("cannot find result from day %s", startDate)
 	}
 
 	endIndex := findResultIndex(endDate, results)
 
 	if endIndex == -1 {
-		log.Println("cannot find result from day", endDate.String())
-		return nil
+		return nil, fmt.Errorf("cannot find result from day %s", endDate)
 	}
 
 	for i := minNumber; i < maxNumber+1; i++ {
@@ -189,12 +154,16 @@ func getNumbersStatistics(
 	for i := startIndex; i < endIndex+1; i++ {
 		iresult := results[i]
 
+		if game != "" && iresult.game != game {
+			continue
+		}
+
 		for _, num := range iresult.numbers {
 			stats[num]++
 		}
 	}
 
-	return stats
+	return stats, nil
 }
 
 func sortStats(stats map[int]int) *orderedmap.OrderedMap[int, int] {
@@ -220,7 +189,7 @@ func sortStats(stats map[int]int) *orderedmap.OrderedMap[int, int] {
 	return newStats
 }
 
-func printStats(sortedStats *orderedmap.OrderedMap[int, int]) {
+func formatStats(sortedStats *orderedmap.OrderedMap[int, int]) string {
 	maxKey := 0
 	maxValue := 0
 
@@ -247,34 +216,78 @@ func printStats(sortedStats *orderedmap.OrderedMap[int, int]) {
 	format += "%" + fmt.Sprint(valueWidth) + "dx"
 	format += "      %v\n"
 
+	var sb strings.Builder
+
 	for el := sortedStats.Front(); el != nil; el = el.Next() {
 		countStr := strings.Repeat("*", el.Value)
 
-		fmt.Printf(format, el.Key, el.Value, countStr)
+		fmt.Fprintf(&sb, format, el.Key, el.Value, countStr)
 	}
+
+	return sb.String()
+}
+
+func printStats(sortedStats *orderedmap.OrderedMap[int, int]) {
+	fmt.Print(formatStats(sortedStats))
 }
 
 func main() {
+	chartPathname := flag.String("chart", "", "write a number frequency histogram to this pathname")
+	chartFormat := flag.String("chart-format", "png", "format for -chart: png or svg")
+	serveAddr := flag.String("serve", "", "if set, serve stats over HTTP on this address (e.g. :8080) instead of exiting")
+	archiveTemplate := flag.String("archive", DEFAULT_ARCHIVE_TEMPLATE, "strftime-style template for the archived results pathname, e.g. './results/%Y/%m/%d-dl.txt'")
+	archiveStats := flag.Bool("archive-stats", false, "also write a .stats.json sidecar next to the archived results file")
+	dbPathname := flag.String("db", "", "if set, persist draws in this SQLite database and only fetch new ones on each run")
+	exportFormat := flag.String("export", "", "dump the store in this format (csv, json or ndjson) and exit; requires -db")
+	recommendStrategy := flag.String("recommend", "", "if set, print top number suggestions using this ranking strategy (most-frequent, overdue, hot-cold, co-occurrence)")
+	recommendTop := flag.Int("top", 10, "how many numbers to suggest for -recommend")
+	recommendWindow := flag.Int("window", 52, "short-window size, in draws, for the hot-cold -recommend strategy")
+	recommendAlpha := flag.Float64("alpha", 0.5, "short-window weight (0-1) for the hot-cold -recommend strategy")
+	jobs := flag.Int("jobs", 4, "how many sources to download concurrently")
+	gameFilter := flag.String("game", "", "restrict stats to this game (e.g. Lotto); empty means every configured source")
+	flag.Parse()
+
+	sources := append([]Source(nil), defaultSources...)
+	sources[0].ArchiveTemplate = *archiveTemplate
+
 	log.Println("Repo URL", REPO_URL)
-	log.Println("Saving downloaded results to", RESULTS_FULL_PATHNAME)
+	log.Println("Archive template", *archiveTemplate)
+
+	var parsed []ResultEntry
+	var pathname string
+	var db *store
+
+	if *dbPathname != "" {
+		parsed, db = loadFromStore(*dbPathname, sources[0])
+		defer db.Close()
+	} else {
+		if *exportFormat != "" {
+			log.Panicln("-export requires -db")
+		}
 
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		parsed = fetchSources(sources, *jobs)
 
-	os.MkdirAll(RESULTS_FULL_PATHNAME, 0777)
+		if len(parsed) == 0 {
+			log.Panicln("cannot find file with the latest results")
+		}
 
-	pathname := downloadLastResultsFile()
+		var err error
 
-	if pathname == "" {
-		log.Panicln("cannot find file with the latest results")
+		pathname, err = downloadLastResultsFile(sources[0])
+		if err != nil {
+			log.Panicln("err", err)
+		}
 	}
 
-	parsed, err := parseResultsFile(pathname)
+	sortResultEntrySlice(parsed)
 
-	if err != nil {
-		log.Panicln("err", err)
-	}
+	if *exportFormat != "" {
+		if err := exportResults(parsed, *exportFormat, os.Stdout); err != nil {
+			log.Panicln("err", err)
+		}
 
-	sortResultEntrySlice(parsed)
+		return
+	}
 
 	endDate := parsed[len(parsed)-1].dateTime
 	startDate := endDate.AddDate(0, 0, -367)
@@ -287,15 +300,71 @@ func main() {
 	log.Println("Start date", startDate)
 	log.Println("End date", endDate)
 
-	stats := getNumbersStatistics(
-		startDate,
-		endDate,
-		1,
-		49,
-		parsed)
+	var stats map[int]int
+	var err error
+
+	if db != nil {
+		// Indexed date_time lookup instead of getNumbersStatistics's linear
+		// scan over the whole in-memory slice.
+		stats, err = db.statsBetween(startDate, endDate, 1, 49, *gameFilter)
+	} else {
+		stats, err = getNumbersStatistics(startDate, endDate, 1, 49, *gameFilter, parsed)
+	}
+
+	if err != nil {
+		log.Panicln("err", err)
+	}
 
 	sortedStats := sortStats(stats)
 
 	log.Println("Sorted results:")
 	printStats(sortedStats)
+
+	if *recommendStrategy != "" {
+		ranker, err := newRanker(*recommendStrategy, 1, 49, *recommendWindow, *recommendAlpha, *gameFilter)
+		if err != nil {
+			log.Panicln("err", err)
+		}
+
+		log.Println("Recommended numbers", ranker.Rank(parsed, *recommendTop))
+	}
+
+	if *archiveStats && pathname != "" {
+		sidecarPathname := statsSidecarPathname(pathname)
+
+		if err := writeStatsSidecar(sortedStats, sidecarPathname); err != nil {
+			log.Panicln("err", err)
+		}
+
+		log.Println("Wrote stats sidecar to", sidecarPathname)
+	}
+
+	if *chartPathname != "" {
+		if err := saveFrequencyChart(sortedStats, *chartPathname, *chartFormat); err != nil {
+			log.Panicln("err", err)
+		}
+
+		log.Println("Wrote frequency chart to", withExtension(*chartPathname, *chartFormat))
+
+		monthlyPathname := monthlyChartPathname(*chartPathname)
+		numbers := make([]int, 0, sortedStats.Len())
+
+		for el := sortedStats.Front(); el != nil; el = el.Next() {
+			numbers = append(numbers, el.Key)
+		}
+
+		if err := saveMonthlyFrequencyChart(parsed, numbers, monthlyPathname, *chartFormat); err != nil {
+			log.Panicln("err", err)
+		}
+
+		log.Println("Wrote monthly frequency chart to", withExtension(monthlyPathname, *chartFormat))
+	}
+
+	if *serveAddr != "" {
+		srv := newStatsServer(parsed, sources[0])
+		go srv.refreshDaily()
+
+		log.Println("Serving stats on", *serveAddr)
+		log.Panicln("err", http.ListenAndServe(*serveAddr, srv.routes()))
+	}
 }
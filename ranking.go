@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// Ranker scores candidate numbers against drawn history and returns the
+// `top` strongest recommendations, from strongest to weakest.
+type Ranker interface {
+	Rank(results []ResultEntry, top int) []int
+}
+
+// topKeys returns the first `top` keys of sortedStats, in order.
+func topKeys(sortedStats *orderedmap.OrderedMap[int, int], top int) []int {
+	keys := make([]int, 0, top)
+
+	for el := sortedStats.Front(); el != nil && len(keys) < top; el = el.Next() {
+		keys = append(keys, el.Key)
+	}
+
+	return keys
+}
+
+// MostFrequentRanker recommends the numbers drawn most often across the
+// whole history.
+type MostFrequentRanker struct {
+	MinNumber int
+	MaxNumber int
+	Game      string
+}
+
+func (r MostFrequentRanker) Rank(results []ResultEntry, top int) []int {
+	if len(results) == 0 {
+		return nil
+	}
+
+	stats, err := getNumbersStatistics(
+		results[0].dateTime,
+		results[len(results)-1].dateTime,
+		r.MinNumber,
+		r.MaxNumber,
+		r.Game,
+		results)
+	if err != nil {
+		return nil
+	}
+
+	return topKeys(sortStats(stats), top)
+}
+
+// OverdueRanker ("least frequent") recommends numbers with the largest gap,
+// in number of draws, since they last appeared.
+type OverdueRanker struct {
+	MinNumber int
+	MaxNumber int
+	Game      string
+}
+
+func (r OverdueRanker) Rank(results []ResultEntry, top int) []int {
+	gaps := drawsSinceLastSeen(filterByGame(results, r.Game), r.MinNumber, r.MaxNumber)
+
+	type numberGap struct {
+		number int
+		gap    int
+	}
+
+	ordered := make([]numberGap, 0, len(gaps))
+
+	for number, gap := range gaps {
+		ordered = append(ordered, numberGap{number, gap})
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].gap != ordered[j].gap {
+			return ordered[i].gap > ordered[j].gap
+		}
+
+		return ordered[i].number < ordered[j].number
+	})
+
+	result := make([]int, 0, top)
+
+	for i := 0; i < top && i < len(ordered); i++ {
+		result = append(result, ordered[i].number)
+	}
+
+	return result
+}
+
+// drawsSinceLastSeen scans results in reverse and returns, for every number
+// in [minNumber, maxNumber], how many draws ago it last appeared. A number
+// that never appeared gets a gap equal to len(results).
+func drawsSinceLastSeen(results []ResultEntry, minNumber int, maxNumber int) map[int]int {
+	gaps := make(map[int]int, maxNumber-minNumber+1)
+
+	for number := minNumber; number <= maxNumber; number++ {
+		gaps[number] = len(results)
+	}
+
+	seen := make(map[int]bool, maxNumber-minNumber+1)
+
+	for i := len(results) - 1; i >= 0; i-- {
+		for _, number := range results[i].numbers {
+			if seen[number] {
+				continue
+			}
+
+			if _, tracked := gaps[number]; !tracked {
+				continue
+			}
+
+			gaps[number] = len(results) - 1 - i
+			seen[number] = true
+		}
+	}
+
+	return gaps
+}
+
+// HotColdRanker blends short-window ("hot") and long-window ("cold")
+// frequencies. Alpha close to 1 favors the short window, close to 0 favors
+// the long window.
+type HotColdRanker struct {
+	MinNumber int
+	MaxNumber int
+	Window    int
+	Alpha     float64
+	Game      string
+}
+
+func (r HotColdRanker) Rank(results []ResultEntry, top int) []int {
+	if len(results) == 0 {
+		return nil
+	}
+
+	longStats, err := getNumbersStatistics(
+		results[0].dateTime,
+		results[len(results)-1].dateTime,
+		r.MinNumber,
+		r.MaxNumber,
+		r.Game,
+		results)
+	if err != nil {
+		return nil
+	}
+
+	window := r.Window
+	if window < 1 {
+		window = 1
+	}
+	if window > len(results) {
+		window = len(results)
+	}
+
+	shortResults := results[len(results)-window:]
+
+	shortStats, err := getNumbersStatistics(
+		shortResults[0].dateTime,
+		shortResults[len(shortResults)-1].dateTime,
+		r.MinNumber,
+		r.MaxNumber,
+		r.Game,
+		shortResults)
+	if err != nil {
+		return nil
+	}
+
+	maxLong := maxValue(longStats)
+	maxShort := maxValue(shortStats)
+
+	type numberScore struct {
+		number int
+		score  float64
+	}
+
+	scores := make([]numberScore, 0, len(longStats))
+
+	for number := r.MinNumber; number <= r.MaxNumber; number++ {
+		longScore := 0.0
+		if maxLong > 0 {
+			longScore = float64(longStats[number]) / float64(maxLong)
+		}
+
+		shortScore := 0.0
+		if maxShort > 0 {
+			shortScore = float64(shortStats[number]) / float64(maxShort)
+		}
+
+		score := r.Alpha*shortScore + (1-r.Alpha)*longScore
+		scores = append(scores, numberScore{number, score})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+
+		return scores[i].number < scores[j].number
+	})
+
+	result := make([]int, 0, top)
+
+	for i := 0; i < top && i < len(scores); i++ {
+		result = append(result, scores[i].number)
+	}
+
+	return result
+}
+
+func maxValue(stats map[int]int) int {
+	max := 0
+
+	for _, count := range stats {
+		if count > max {
+			max = count
+		}
+	}
+
+	return max
+}
+
+// CoOccurrenceRanker builds a minNumber..maxNumber co-occurrence matrix,
+// counting how often each pair of numbers was drawn together, then greedily
+// picks `top` numbers maximizing the summed pairwise weight with the
+// numbers picked so far.
+type CoOccurrenceRanker struct {
+	MinNumber int
+	MaxNumber int
+	Game      string
+}
+
+func (r CoOccurrenceRanker) Rank(results []ResultEntry, top int) []int {
+	pairWeights := make(map[[2]int]int)
+
+	for _, entry := range filterByGame(results, r.Game) {
+		for i := 0; i < len(entry.numbers); i++ {
+			for j := i + 1; j < len(entry.numbers); j++ {
+				pairWeights[pairKey(entry.numbers[i], entry.numbers[j])]++
+			}
+		}
+	}
+
+	picked := make([]int, 0, top)
+	remaining := make(map[int]bool, r.MaxNumber-r.MinNumber+1)
+
+	for number := r.MinNumber; number <= r.MaxNumber; number++ {
+		remaining[number] = true
+	}
+
+	// seed with the number involved in the single heaviest pair.
+	bestPair := [2]int{}
+	bestWeight := -1
+
+	for pair, weight := range pairWeights {
+		if weight > bestWeight {
+			bestWeight = weight
+			bestPair = pair
+		}
+	}
+
+	if bestWeight >= 0 && top > 0 {
+		picked = append(picked, bestPair[0])
+		delete(remaining, bestPair[0])
+	}
+
+	for len(picked) < top && len(remaining) > 0 {
+		bestCandidate := 0
+		bestScore := -1
+
+		for candidate := range remaining {
+			score := 0
+
+			for _, p := range picked {
+				score += pairWeights[pairKey(candidate, p)]
+			}
+
+			if score > bestScore || (score == bestScore && candidate < bestCandidate) {
+				bestScore = score
+				bestCandidate = candidate
+			}
+		}
+
+		picked = append(picked, bestCandidate)
+		delete(remaining, bestCandidate)
+	}
+
+	return picked
+}
+
+func pairKey(a int, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+
+	return [2]int{a, b}
+}
+
+// filterByGame returns the entries tagged with game, or results unchanged
+// if game is empty.
+func filterByGame(results []ResultEntry, game string) []ResultEntry {
+	if game == "" {
+		return results
+	}
+
+	filtered := make([]ResultEntry, 0, len(results))
+
+	for _, entry := range results {
+		if entry.game == game {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// newRanker builds the Ranker named by strategy ("most-frequent",
+// "overdue", "hot-cold" or "co-occurrence").
+func newRanker(strategy string, minNumber int, maxNumber int, window int, alpha float64, game string) (Ranker, error) {
+	switch strategy {
+	case "most-frequent":
+		return MostFrequentRanker{MinNumber: minNumber, MaxNumber: maxNumber, Game: game}, nil
+	case "overdue", "least-frequent":
+		return OverdueRanker{MinNumber: minNumber, MaxNumber: maxNumber, Game: game}, nil
+	case "hot-cold":
+		if window < 1 {
+			return nil, fmt.Errorf("-window must be at least 1, got %d", window)
+		}
+
+		return HotColdRanker{MinNumber: minNumber, MaxNumber: maxNumber, Window: window, Alpha: alpha, Game: game}, nil
+	case "co-occurrence":
+		return CoOccurrenceRanker{MinNumber: minNumber, MaxNumber: maxNumber, Game: game}, nil
+	default:
+		return nil, fmt.Errorf("unknown ranking strategy %q", strategy)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixtureResults is a small synthetic history used to lock in expected
+// ranking output: 1 and 2 are drawn every time, 3 only in the first two
+// draws, and every other number appears exactly once.
+func fixtureResults() []ResultEntry {
+	day := func(n int) time.Time {
+		return time.Date(2024, time.January, n, 0, 0, 0, 0, CURRENT_LOCATION)
+	}
+
+	entries := []ResultEntry{
+		{seqNo: 1, dateTime: day(1), numbers: [6]int{1, 2, 3, 4, 5, 6}},
+		{seqNo: 2, dateTime: day(2), numbers: [6]int{1, 2, 3, 7, 8, 9}},
+		{seqNo: 3, dateTime: day(3), numbers: [6]int{1, 2, 10, 11, 12, 13}},
+	}
+
+	sortResultEntrySlice(entries)
+
+	return entries
+}
+
+func TestMostFrequentRanker(t *testing.T) {
+	ranker := MostFrequentRanker{MinNumber: 1, MaxNumber: 13}
+
+	got := ranker.Rank(fixtureResults(), 3)
+	want := []int{1, 2, 3}
+
+	if !containsSameElements(got, want) {
+		t.Errorf("Rank() = %v, want the 3 most frequent numbers %v", got, want)
+	}
+}
+
+func TestOverdueRanker(t *testing.T) {
+	ranker := OverdueRanker{MinNumber: 1, MaxNumber: 13}
+
+	got := ranker.Rank(fixtureResults(), 1)
+	want := []int{4}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %v, want %v (4 hasn't appeared since the oldest draw)", got, want)
+	}
+}
+
+func TestHotColdRankerFavorsRecentDraws(t *testing.T) {
+	ranker := HotColdRanker{MinNumber: 1, MaxNumber: 13, Window: 1, Alpha: 1}
+
+	got := ranker.Rank(fixtureResults(), 6)
+	want := []int{1, 2, 10, 11, 12, 13}
+
+	if !containsSameElements(got, want) {
+		t.Errorf("Rank() = %v, want exactly the numbers from the last draw %v", got, want)
+	}
+}
+
+func TestHotColdRankerClampsNonPositiveWindow(t *testing.T) {
+	ranker := HotColdRanker{MinNumber: 1, MaxNumber: 13, Window: 0, Alpha: 1}
+
+	got := ranker.Rank(fixtureResults(), 6)
+	want := []int{1, 2, 10, 11, 12, 13}
+
+	if !containsSameElements(got, want) {
+		t.Errorf("Rank() = %v, want the last draw %v (window clamped to 1 instead of panicking)", got, want)
+	}
+}
+
+func TestCoOccurrenceRankerPicksRequestedCount(t *testing.T) {
+	ranker := CoOccurrenceRanker{MinNumber: 1, MaxNumber: 13}
+
+	got := ranker.Rank(fixtureResults(), 6)
+
+	if len(got) != 6 {
+		t.Fatalf("Rank() returned %d numbers, want 6", len(got))
+	}
+
+	seen := make(map[int]bool)
+	for _, number := range got {
+		if seen[number] {
+			t.Fatalf("Rank() = %v, contains duplicate number %d", got, number)
+		}
+		seen[number] = true
+	}
+}
+
+func containsSameElements(got []int, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	wantSet := make(map[int]bool, len(want))
+	for _, number := range want {
+		wantSet[number] = true
+	}
+
+	for _, number := range got {
+		if !wantSet[number] {
+			return false
+		}
+	}
+
+	return true
+}
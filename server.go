@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// statsServer keeps the parsed draws in memory and exposes them over HTTP.
+type statsServer struct {
+	mu      sync.RWMutex
+	results []ResultEntry
+	source  Source
+}
+
+func newStatsServer(results []ResultEntry, source Source) *statsServer {
+	return &statsServer{results: results, source: source}
+}
+
+// numberCount is the JSON representation of one entry of a sorted stats map.
+type numberCount struct {
+	Number int `json:"number"`
+	Count  int `json:"count"`
+}
+
+// resultEntryJSON is the JSON representation of a ResultEntry, whose own
+// fields are unexported.
+type resultEntryJSON struct {
+	SeqNo    int       `json:"seqNo"`
+	DateTime time.Time `json:"dateTime"`
+	Numbers  [6]int    `json:"numbers"`
+	Game     string    `json:"game"`
+}
+
+func toResultEntryJSON(entry ResultEntry) resultEntryJSON {
+	return resultEntryJSON{
+		SeqNo:    entry.seqNo,
+		DateTime: entry.dateTime,
+		Numbers:  entry.numbers,
+		Game:     entry.game,
+	}
+}
+
+func (s *statsServer) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/stats.html", s.handleStatsHTML)
+	mux.HandleFunc("/draws/", s.handleDraw)
+
+	return mux
+}
+
+// refreshDaily re-downloads and re-parses the results file once a day so a
+// long-running server picks up new draws without a restart.
+func (s *statsServer) refreshDaily() {
+	for range time.Tick(24 * time.Hour) {
+		pathname, err := downloadLastResultsFile(s.source)
+		if err != nil {
+			log.Println("refreshDaily: err", err)
+			continue
+		}
+
+		if pathname == "" {
+			log.Println("refreshDaily: cannot find file with the latest results")
+			continue
+		}
+
+		parsed, err := parseResultsFile(pathname, s.source.FormatRe, s.source.Game)
+
+		if err != nil {
+			log.Println("refreshDaily: err", err)
+			continue
+		}
+
+		if len(parsed) == 0 {
+			log.Println("refreshDaily: parsed 0 draws, keeping existing results")
+			continue
+		}
+
+		sortResultEntrySlice(parsed)
+
+		s.mu.Lock()
+		s.results = parsed
+		s.mu.Unlock()
+
+		log.Println("refreshDaily: reloaded", len(parsed), "draws")
+	}
+}
+
+// parseStatsQuery extracts the from/to/min/max query parameters used by
+// /stats, falling back to the full history and 1-49 when absent.
+func parseStatsQuery(q url.Values, results []ResultEntry) (time.Time, time.Time, int, int, string, error) {
+	startDate := results[0].dateTime
+	endDate := results[len(results)-1].dateTime
+	minNumber := 1
+	maxNumber := 49
+	game := q.Get("game")
+
+	if from := q.Get("from"); from != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", from, CURRENT_LOCATION)
+		if err != nil {
+			return startDate, endDate, minNumber, maxNumber, game, fmt.Errorf("invalid from: %w", err)
+		}
+		startDate = parsed
+	}
+
+	if to := q.Get("to"); to != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", to, CURRENT_LOCATION)
+		if err != nil {
+			return startDate, endDate, minNumber, maxNumber, game, fmt.Errorf("invalid to: %w", err)
+		}
+		endDate = parsed
+	}
+
+	if min := q.Get("min"); min != "" {
+		parsed, err := strconv.Atoi(min)
+		if err != nil {
+			return startDate, endDate, minNumber, maxNumber, game, fmt.Errorf("invalid min: %w", err)
+		}
+		minNumber = parsed
+	}
+
+	if max := q.Get("max"); max != "" {
+		parsed, err := strconv.Atoi(max)
+		if err != nil {
+			return startDate, endDate, minNumber, maxNumber, game, fmt.Errorf("invalid max: %w", err)
+		}
+		maxNumber = parsed
+	}
+
+	return startDate, endDate, minNumber, maxNumber, game, nil
+}
+
+func (s *statsServer) loadSortedStats(r *http.Request) (*orderedmap.OrderedMap[int, int], error) {
+	s.mu.RLock()
+	results := s.results
+	s.mu.RUnlock()
+
+	startDate, endDate, minNumber, maxNumber, game, err := parseStatsQuery(r.URL.Query(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := getNumbersStatistics(startDate, endDate, minNumber, maxNumber, game, results)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortStats(stats), nil
+}
+
+func (s *statsServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	sortedStats, err := s.loadSortedStats(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	counts := make([]numberCount, 0, sortedStats.Len())
+
+	for el := sortedStats.Front(); el != nil; el = el.Next() {
+		counts = append(counts, numberCount{Number: el.Key, Count: el.Value})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
+
+func (s *statsServer) handleStatsHTML(w http.ResponseWriter, r *http.Request) {
+	sortedStats, err := s.loadSortedStats(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, "<!DOCTYPE html><html><body><pre>")
+	fmt.Fprint(w, html.EscapeString(formatStats(sortedStats)))
+	fmt.Fprint(w, "</pre></body></html>")
+}
+
+func (s *statsServer) handleDraw(w http.ResponseWriter, r *http.Request) {
+	seqNoStr := strings.TrimPrefix(r.URL.Path, "/draws/")
+
+	seqNo, err := strconv.Atoi(seqNoStr)
+	if err != nil {
+		http.Error(w, "invalid seqNo", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, entry := range s.results {
+		if entry.seqNo == seqNo {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(toResultEntryJSON(entry))
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
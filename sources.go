@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Source describes one result feed the tool can download and parse: its
+// remote URL, the regex used to recognize result lines, where to archive
+// the downloaded file, and the Game tag applied to entries parsed from it.
+type Source struct {
+	ID                 string
+	Game               string
+	URL                string
+	FormatRe           *regexp.Regexp
+	ArchiveTemplate    string
+	InsecureSkipVerify bool
+}
+
+// defaultSources is the built-in registry. Today it only covers
+// mbnet.com.pl's Lotto feed, but additional games (Mini Lotto, Multi Multi,
+// ...) can be registered here the same way, each with its own URL, FormatRe
+// and archive template.
+var defaultSources = []Source{
+	{
+		ID:                 "lotto",
+		Game:               "Lotto",
+		URL:                REPO_URL,
+		FormatRe:           RESULT_FORMAT_RE,
+		ArchiveTemplate:    DEFAULT_ARCHIVE_TEMPLATE,
+		InsecureSkipVerify: true,
+	},
+}
+
+// httpClient returns an *http.Client configured for s. Unlike patching
+// http.DefaultTransport globally, InsecureSkipVerify is opt-in per source.
+func (s Source) httpClient() *http.Client {
+	if !s.InsecureSkipVerify {
+		return http.DefaultClient
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
+
+// downloadLastResultsFile downloads (or reuses an already-archived copy of)
+// s's results file and returns its pathname. HTTP/IO failures are returned
+// as an error rather than panicking, so a single bad source doesn't bring
+// down a whole concurrent fetchSources run or a long-running -serve process.
+func downloadLastResultsFile(s Source) (string, error) {
+	now := time.Now()
+
+	latestResultPathname := expandTemplate(s.ArchiveTemplate, now)
+
+	log.Println("Looking for", latestResultPathname)
+
+	if _, err := os.Stat(latestResultPathname); err == nil {
+		log.Println("Latest file with results already exists in", latestResultPathname)
+
+		return latestResultPathname, nil
+	}
+
+	log.Println("Latest file with results does not exists, downloading", s.URL, "to", latestResultPathname)
+
+	if err := os.MkdirAll(filepath.Dir(latestResultPathname), 0777); err != nil {
+		return "", err
+	}
+
+	response, err := s.httpClient().Get(s.URL)
+
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	resBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(latestResultPathname, resBody, 0666); err != nil {
+		return "", err
+	}
+
+	return latestResultPathname, nil
+}
+
+// fetchSource downloads and parses a single source's results file.
+func fetchSource(s Source) ([]ResultEntry, error) {
+	pathname, err := downloadLastResultsFile(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find file with the latest results for source %q: %w", s.ID, err)
+	}
+
+	if pathname == "" {
+		return nil, fmt.Errorf("cannot find file with the latest results for source %q", s.ID)
+	}
+
+	return parseResultsFile(pathname, s.FormatRe, s.Game)
+}
+
+// fetchSources downloads and parses every source concurrently, bounded by a
+// worker pool of the given size, and returns the combined entries, each
+// tagged with its source's Game.
+func fetchSources(sources []Source, jobs int) []ResultEntry {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	ids := make(chan string, len(sources))
+	byID := make(map[string]Source, len(sources))
+
+	for _, s := range sources {
+		byID[s.ID] = s
+		ids <- s.ID
+	}
+	close(ids)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []ResultEntry
+	)
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for id := range ids {
+				entries, err := fetchSource(byID[id])
+				if err != nil {
+					log.Println("fetchSources:", id, "err", err)
+					continue
+				}
+
+				mu.Lock()
+				results = append(results, entries...)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
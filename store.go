@@ -0,0 +1,315 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// tailFetchBytes is how much of the end of a source's URL we ask for via a Range
+// request when only looking for draws newer than maxSeqNo.
+const tailFetchBytes = 64 * 1024
+
+// fetchResultsTail downloads just the new draws from s: a Range request for
+// the trailing tailFetchBytes bytes if the server honors it, falling back
+// to a full GET otherwise. Entries with seqNo <= maxSeqNo are dropped. When
+// maxSeqNo is 0 (an empty store, e.g. first run), the tail trick would
+// silently drop every older draw, so the whole file is fetched instead.
+func fetchResultsTail(s Source, maxSeqNo int) ([]ResultEntry, error) {
+	var body []byte
+	var err error
+
+	if maxSeqNo == 0 {
+		body, err = fetchFullBytes(s, s.URL)
+	} else {
+		body, err = fetchTailBytes(s, s.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := parseResultsString(string(body), s.FormatRe, s.Game)
+
+	fresh := make([]ResultEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.seqNo > maxSeqNo {
+			fresh = append(fresh, entry)
+		}
+	}
+
+	return fresh, nil
+}
+
+func fetchTailBytes(s Source, url string) ([]byte, error) {
+	client := s.httpClient()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=-%d", tailFetchBytes))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPartialContent {
+		return io.ReadAll(resp.Body)
+	}
+
+	// server doesn't honor Range (or doesn't know the resource size yet);
+	// fall back to downloading the whole file.
+	resp.Body.Close()
+
+	fullResp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer fullResp.Body.Close()
+
+	return io.ReadAll(fullResp.Body)
+}
+
+// fetchFullBytes downloads the entirety of url with a plain GET.
+func fetchFullBytes(s Source, url string) ([]byte, error) {
+	resp, err := s.httpClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func parseStoredDate(dateStr string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", dateStr, CURRENT_LOCATION)
+}
+
+// refreshStore fetches and upserts into s any draws newer than what is
+// already stored there.
+func refreshStore(s *store, source Source) error {
+	maxSeqNo, err := s.maxSeqNo()
+	if err != nil {
+		return err
+	}
+
+	fresh, err := fetchResultsTail(source, maxSeqNo)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Fetched", len(fresh), "new draws since seqNo", maxSeqNo)
+
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	return s.upsertAll(fresh)
+}
+
+// loadFromStore opens the SQLite database at dbPathname, fetches and upserts
+// any draws newer than what is already stored, and returns every draw along
+// with the open store, whose indexed date_time lookups (statsBetween) let
+// callers answer stats queries without the caller's own linear scan. The
+// caller owns the returned store and must Close it.
+func loadFromStore(dbPathname string, source Source) ([]ResultEntry, *store) {
+	s, err := openStore(dbPathname)
+	if err != nil {
+		log.Panicln("err", err)
+	}
+
+	if err := refreshStore(s, source); err != nil {
+		log.Panicln("err", err)
+	}
+
+	results, err := s.loadAll()
+	if err != nil {
+		log.Panicln("err", err)
+	}
+
+	return results, s
+}
+
+// store persists ResultEntry values in SQLite, keyed by seqNo, so repeated
+// runs don't have to re-parse the whole archive to get statistics.
+type store struct {
+	db *sql.DB
+}
+
+// openStore opens (creating if necessary) the SQLite database at pathname
+// and ensures the draws table exists.
+func openStore(pathname string) (*store, error) {
+	db, err := sql.Open("sqlite", pathname)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draws (
+			seq_no    INTEGER PRIMARY KEY,
+			date_time TEXT NOT NULL,
+			n0        INTEGER NOT NULL,
+			n1        INTEGER NOT NULL,
+			n2        INTEGER NOT NULL,
+			n3        INTEGER NOT NULL,
+			n4        INTEGER NOT NULL,
+			n5        INTEGER NOT NULL,
+			game      TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_draws_date_time ON draws (date_time)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &store{db: db}, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+// maxSeqNo returns the highest seqNo currently stored, or 0 if the store is
+// empty.
+func (s *store) maxSeqNo() (int, error) {
+	var maxSeqNo sql.NullInt64
+
+	err := s.db.QueryRow("SELECT MAX(seq_no) FROM draws").Scan(&maxSeqNo)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(maxSeqNo.Int64), nil
+}
+
+// upsertAll inserts entries into the store, replacing any existing row with
+// the same seqNo.
+func (s *store) upsertAll(entries []ResultEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO draws (seq_no, date_time, n0, n1, n2, n3, n4, n5, game)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(seq_no) DO UPDATE SET
+			date_time = excluded.date_time,
+			n0 = excluded.n0, n1 = excluded.n1, n2 = excluded.n2,
+			n3 = excluded.n3, n4 = excluded.n4, n5 = excluded.n5,
+			game = excluded.game
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		_, err := stmt.Exec(
+			entry.seqNo,
+			entry.dateTime.Format("2006-01-02"),
+			entry.numbers[0], entry.numbers[1], entry.numbers[2],
+			entry.numbers[3], entry.numbers[4], entry.numbers[5],
+			entry.game,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// loadAll returns every draw in the store, ordered by seqNo.
+func (s *store) loadAll() ([]ResultEntry, error) {
+	rows, err := s.db.Query("SELECT seq_no, date_time, n0, n1, n2, n3, n4, n5, game FROM draws ORDER BY seq_no")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]ResultEntry, 0)
+
+	for rows.Next() {
+		var entry ResultEntry
+		var dateStr string
+
+		err := rows.Scan(
+			&entry.seqNo, &dateStr,
+			&entry.numbers[0], &entry.numbers[1], &entry.numbers[2],
+			&entry.numbers[3], &entry.numbers[4], &entry.numbers[5],
+			&entry.game,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.dateTime, err = parseStoredDate(dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("bad date_time for seqNo %d: %w", entry.seqNo, err)
+		}
+
+		results = append(results, entry)
+	}
+
+	return results, rows.Err()
+}
+
+// statsBetween counts how often each number in [minNumber, maxNumber] was
+// drawn between startDate and endDate (inclusive), using the indexed
+// date_time column to select the matching rows instead of the O(N) linear
+// scan getNumbersStatistics does over an in-memory slice.
+func (s *store) statsBetween(startDate time.Time, endDate time.Time, minNumber int, maxNumber int, game string) (map[int]int, error) {
+	stats := make(map[int]int)
+	for i := minNumber; i < maxNumber+1; i++ {
+		stats[i] = 0
+	}
+
+	query := `SELECT n0, n1, n2, n3, n4, n5, game FROM draws WHERE date_time BETWEEN ? AND ?`
+	args := []interface{}{startDate.Format("2006-01-02"), endDate.Format("2006-01-02")}
+
+	if game != "" {
+		query += " AND game = ?"
+		args = append(args, game)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var numbers [6]int
+		var rowGame string
+
+		if err := rows.Scan(&numbers[0], &numbers[1], &numbers[2], &numbers[3], &numbers[4], &numbers[5], &rowGame); err != nil {
+			return nil, err
+		}
+
+		for _, num := range numbers {
+			if num >= minNumber && num <= maxNumber {
+				stats[num]++
+			}
+		}
+	}
+
+	return stats, rows.Err()
+}